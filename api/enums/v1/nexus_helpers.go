@@ -0,0 +1,104 @@
+// The MIT License
+//
+// Copyright (c) 2024 Temporal Technologies Inc.  All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package enums
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// This file is a hand-written stopgap for NexusOperationState only. The long-term home for
+// Shorthand/MarshalJSON/UnmarshalJSON/Values is the protoc-gen-go-helpers generator template, so
+// every enum in the codebase gets them consistently - but that generator's source isn't checked
+// into this tree, so it can't be extended here. Once it can be, these should move into
+// nexus.go-helpers.pb.go as generated output and this file should be deleted.
+
+var nexusOperationStateShorthandName = map[int32]string{
+	0: "Unspecified",
+	1: "Scheduled",
+	2: "BackingOff",
+	3: "Started",
+	4: "Succeeded",
+	5: "Failed",
+	6: "Canceled",
+	7: "TimedOut",
+}
+
+// Shorthand returns the traditional temporal PascalCase name for this NexusOperationState value,
+// e.g. "BackingOff" rather than the protojson canonical "BACKING_OFF".
+func (x NexusOperationState) Shorthand() string {
+	if name, ok := nexusOperationStateShorthandName[int32(x)]; ok {
+		return name
+	}
+	return NexusOperationState_name[int32(x)]
+}
+
+// MarshalJSON implements json.Marshaler, emitting the traditional temporal PascalCase shorthand
+// form, e.g. "BackingOff", instead of the protojson canonical SCREAMING_CASE form.
+func (x NexusOperationState) MarshalJSON() ([]byte, error) {
+	name := x.Shorthand()
+	if name == "" {
+		return nil, fmt.Errorf("%d is not a valid NexusOperationState", int32(x))
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It prefers the traditional temporal PascalCase
+// shorthand form but also tolerates the protojson canonical SCREAMING_CASE form and a plain
+// integer, so it round-trips protojson payloads produced by other clients.
+func (x *NexusOperationState) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := NexusOperationStateFromString(s)
+		if err != nil {
+			return err
+		}
+		*x = v
+		return nil
+	}
+
+	var i int32
+	if err := json.Unmarshal(data, &i); err != nil {
+		return fmt.Errorf("%s is not a valid NexusOperationState", string(data))
+	}
+	*x = NexusOperationState(i)
+	return nil
+}
+
+// NexusOperationStateValues returns all valid NexusOperationState values.
+func NexusOperationStateValues() []NexusOperationState {
+	return []NexusOperationState{
+		NexusOperationState(0),
+		NexusOperationState(1),
+		NexusOperationState(2),
+		NexusOperationState(3),
+		NexusOperationState(4),
+		NexusOperationState(5),
+		NexusOperationState(6),
+		NexusOperationState(7),
+	}
+}
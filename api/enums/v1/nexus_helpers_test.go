@@ -0,0 +1,68 @@
+// The MIT License
+//
+// Copyright (c) 2024 Temporal Technologies Inc.  All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package enums
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNexusOperationState_Shorthand(t *testing.T) {
+	require.Equal(t, "BackingOff", NexusOperationState(2).Shorthand())
+}
+
+func TestNexusOperationState_JSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(NexusOperationState(2))
+	require.NoError(t, err)
+	require.Equal(t, `"BackingOff"`, string(data))
+
+	var x NexusOperationState
+	require.NoError(t, json.Unmarshal(data, &x))
+	require.Equal(t, NexusOperationState(2), x)
+}
+
+func TestNexusOperationState_UnmarshalJSON_TolerantForms(t *testing.T) {
+	var fromScreamingCase NexusOperationState
+	require.NoError(t, json.Unmarshal([]byte(`"NEXUS_OPERATION_STATE_BACKING_OFF"`), &fromScreamingCase))
+	require.Equal(t, NexusOperationState(2), fromScreamingCase)
+
+	var fromInt NexusOperationState
+	require.NoError(t, json.Unmarshal([]byte(`2`), &fromInt))
+	require.Equal(t, NexusOperationState(2), fromInt)
+
+	fromInt = NexusOperationState(2)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &fromInt))
+	require.Equal(t, NexusOperationState(2), fromInt, "null should leave the value untouched")
+}
+
+func TestNexusOperationState_UnmarshalJSON_Invalid(t *testing.T) {
+	var x NexusOperationState
+	require.Error(t, json.Unmarshal([]byte(`"NotAState"`), &x))
+}
+
+func TestNexusOperationStateValues(t *testing.T) {
+	require.Len(t, NexusOperationStateValues(), 8)
+	require.Contains(t, NexusOperationStateValues(), NexusOperationState(2))
+}
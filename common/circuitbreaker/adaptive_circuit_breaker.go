@@ -0,0 +1,215 @@
+package circuitbreaker
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"go.temporal.io/server/common/number"
+)
+
+type (
+	// AdaptiveTwoStepCircuitBreaker is a TwoStepCircuitBreaker that trips based on an
+	// exponentially-weighted moving average of the error rate and observed latency of calls made
+	// through it, rather than raw counts over a fixed Interval window, similar to the rate-monitor
+	// pattern used by the flowcontrol package. On every done(success) call, each EMA is updated as
+	// ema = ema + alpha*(sample-ema), where alpha = 1 - exp(-dt/tau) for the configured EMATau.
+	// Because the EMAs are time-normalized rather than window-bound, the breaker reacts
+	// consistently under both bursty and steady load.
+	AdaptiveTwoStepCircuitBreaker struct {
+		core *coreBreaker
+
+		settingsFn   func() map[string]any
+		baseSettings adaptiveSettings
+
+		name          string
+		onStateChange func(name string, from gobreaker.State, to gobreaker.State)
+
+		emaMu        sync.Mutex
+		errorRateEMA float64
+		latencyEMA   time.Duration
+		lastSampleAt time.Time
+	}
+
+	adaptiveSettings struct {
+		MaxRequests        uint32
+		Timeout            time.Duration
+		ErrorRateThreshold float64
+		LatencyThreshold   time.Duration
+		EMATau             time.Duration
+	}
+)
+
+var _ TwoStepCircuitBreaker = (*AdaptiveTwoStepCircuitBreaker)(nil)
+
+const (
+	adaptiveMaxRequestsKey = "MaxRequests"
+	adaptiveTimeoutKey     = "timeout"
+	errorRateThresholdKey  = "ErrorRateThreshold"
+	latencyThresholdMsKey  = "LatencyThresholdMs"
+	emaTauKey              = "EMATau"
+
+	defaultAdaptiveMaxRequests = uint32(0)
+	defaultAdaptiveTimeout     = 0 * time.Second
+	// A threshold of 1 never trips on error rate alone unless it is configured explicitly.
+	defaultErrorRateThreshold = float64(1)
+	// Zero disables the latency-based trip condition.
+	defaultLatencyThresholdMs = 0
+	defaultEMATau             = 30 * time.Second
+)
+
+func NewAdaptiveTwoStepCircuitBreaker(
+	settingsFn func() map[string]any,
+) *AdaptiveTwoStepCircuitBreaker {
+	return &AdaptiveTwoStepCircuitBreaker{
+		settingsFn: settingsFn,
+	}
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) WithName(
+	name string,
+) *AdaptiveTwoStepCircuitBreaker {
+	if a == nil {
+		return nil
+	}
+	return &AdaptiveTwoStepCircuitBreaker{
+		settingsFn:    a.settingsFn,
+		name:          name,
+		onStateChange: a.onStateChange,
+	}
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) WithOnStateChange(
+	onStateChange func(name string, from gobreaker.State, to gobreaker.State),
+) *AdaptiveTwoStepCircuitBreaker {
+	if a == nil {
+		return nil
+	}
+	return &AdaptiveTwoStepCircuitBreaker{
+		settingsFn:    a.settingsFn,
+		name:          a.name,
+		onStateChange: onStateChange,
+	}
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) Name() string {
+	if a.core == nil {
+		return ""
+	}
+	return a.core.Name()
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) State() gobreaker.State {
+	if a.core == nil {
+		return 0
+	}
+	return a.core.State()
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) Counts() gobreaker.Counts {
+	if a.core == nil {
+		return gobreaker.Counts{}
+	}
+	return a.core.Counts()
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) Allow() (done func(success bool), err error) {
+	core := a.checkAndUpdateSettings()
+
+	start := time.Now()
+	coreDone, err := core.Allow()
+	if err != nil {
+		return nil, err
+	}
+	return func(success bool) {
+		a.recordSample(success, time.Since(start))
+		coreDone(success)
+	}, nil
+}
+
+// recordSample updates the error-rate and latency EMAs under emaMu, which also guards
+// baseSettings and the lazy core creation in checkAndUpdateSettings. It is intentionally
+// independent of the coreBreaker's internal mutex since the EMAs are read by the readyToTrip
+// closure that the core invokes while already holding its own lock.
+func (a *AdaptiveTwoStepCircuitBreaker) recordSample(success bool, latency time.Duration) {
+	a.emaMu.Lock()
+	defer a.emaMu.Unlock()
+
+	errorSample := float64(0)
+	if !success {
+		errorSample = 1
+	}
+
+	now := time.Now()
+	if a.lastSampleAt.IsZero() {
+		a.errorRateEMA = errorSample
+		a.latencyEMA = latency
+	} else {
+		alpha := 1 - math.Exp(-float64(now.Sub(a.lastSampleAt))/float64(a.baseSettings.EMATau))
+		a.errorRateEMA += alpha * (errorSample - a.errorRateEMA)
+		a.latencyEMA += time.Duration(alpha * float64(latency-a.latencyEMA))
+	}
+	a.lastSampleAt = now
+}
+
+func (a *AdaptiveTwoStepCircuitBreaker) readyToTrip(gobreaker.Counts) bool {
+	a.emaMu.Lock()
+	defer a.emaMu.Unlock()
+
+	if a.errorRateEMA > a.baseSettings.ErrorRateThreshold {
+		return true
+	}
+	return a.baseSettings.LatencyThreshold > 0 && a.latencyEMA >= a.baseSettings.LatencyThreshold
+}
+
+// checkAndUpdateSettings refreshes baseSettings from settingsFn, lazily creates the underlying
+// coreBreaker on first use, and returns it for the caller to use without a further unguarded read
+// of a.core.
+func (a *AdaptiveTwoStepCircuitBreaker) checkAndUpdateSettings() *coreBreaker {
+	settingsMap := a.settingsFn()
+	bs := adaptiveSettings{
+		MaxRequests:        defaultAdaptiveMaxRequests,
+		Timeout:            defaultAdaptiveTimeout,
+		ErrorRateThreshold: defaultErrorRateThreshold,
+		LatencyThreshold:   defaultLatencyThresholdMs,
+		EMATau:             defaultEMATau,
+	}
+
+	if maxRequests, ok := settingsMap[adaptiveMaxRequestsKey]; ok {
+		bs.MaxRequests = uint32(
+			number.NewNumber(maxRequests).GetUintOrDefault(uint(defaultAdaptiveMaxRequests)),
+		)
+	}
+	if timeout, ok := settingsMap[adaptiveTimeoutKey]; ok {
+		bs.Timeout = time.Duration(
+			number.NewNumber(timeout).GetIntOrDefault(int(defaultAdaptiveTimeout.Seconds())),
+		) * time.Second
+	}
+	if errorRateThreshold, ok := settingsMap[errorRateThresholdKey]; ok {
+		bs.ErrorRateThreshold = number.NewNumber(errorRateThreshold).GetFloatOrDefault(defaultErrorRateThreshold)
+	}
+	if latencyThresholdMs, ok := settingsMap[latencyThresholdMsKey]; ok {
+		bs.LatencyThreshold = time.Duration(
+			number.NewNumber(latencyThresholdMs).GetIntOrDefault(defaultLatencyThresholdMs),
+		) * time.Millisecond
+	}
+	if emaTau, ok := settingsMap[emaTauKey]; ok {
+		bs.EMATau = time.Duration(
+			number.NewNumber(emaTau).GetIntOrDefault(int(defaultEMATau.Seconds())),
+		) * time.Second
+	}
+
+	// emaMu also guards baseSettings and the lazy core creation below, since recordSample and
+	// readyToTrip read baseSettings under the same lock from concurrent done() callbacks.
+	a.emaMu.Lock()
+	a.baseSettings = bs
+	if a.core == nil {
+		a.core = newCoreBreaker(a.name)
+	}
+	core := a.core
+	a.emaMu.Unlock()
+
+	core.updateSettings(bs.MaxRequests, 0, bs.Timeout, a.readyToTrip, a.onStateChange)
+	return core
+}
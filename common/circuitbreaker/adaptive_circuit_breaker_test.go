@@ -0,0 +1,63 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveTwoStepCircuitBreaker_TripsOnSteadyErrorRate(t *testing.T) {
+	settings := map[string]any{
+		errorRateThresholdKey: 0.5,
+		emaTauKey:             1, // seconds; short tau so a handful of calls saturate the EMA.
+	}
+	cb := NewAdaptiveTwoStepCircuitBreaker(func() map[string]any { return settings })
+
+	for i := 0; i < 20 && cb.State() != gobreaker.StateOpen; i++ {
+		done, err := cb.Allow()
+		require.NoError(t, err)
+		done(false)
+	}
+
+	require.Equal(t, gobreaker.StateOpen, cb.State())
+}
+
+func TestAdaptiveTwoStepCircuitBreaker_TripsOnLatency(t *testing.T) {
+	settings := map[string]any{
+		errorRateThresholdKey: 1.0, // effectively disabled; only latency should trip.
+		latencyThresholdMsKey: 5,
+		emaTauKey:             1,
+	}
+	cb := NewAdaptiveTwoStepCircuitBreaker(func() map[string]any { return settings })
+
+	for i := 0; i < 20 && cb.State() != gobreaker.StateOpen; i++ {
+		done, err := cb.Allow()
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		done(true)
+	}
+
+	require.Equal(t, gobreaker.StateOpen, cb.State())
+}
+
+func TestAdaptiveTwoStepCircuitBreaker_ConcurrentAllowIsRaceFree(t *testing.T) {
+	settings := map[string]any{errorRateThresholdKey: 0.9}
+	cb := NewAdaptiveTwoStepCircuitBreaker(func() map[string]any { return settings })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			done, err := cb.Allow()
+			if err != nil {
+				return
+			}
+			done(i%2 == 0)
+		}(i)
+	}
+	wg.Wait()
+}
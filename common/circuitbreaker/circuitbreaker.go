@@ -15,12 +15,13 @@ type (
 		Allow() (done func(success bool), err error)
 	}
 
-	// TwoStepCircuitBreakerWithDynamicSettings is a wrapper of gobreaker.TwoStepCircuitBreaker
-	// that calls the settingsFn everytime the Allow function is called and replaces the circuit
-	// breaker if there is a change in the settings object. Note that in this case, the previous
-	// state of the circuit breaker is lost.
+	// TwoStepCircuitBreakerWithDynamicSettings is a TwoStepCircuitBreaker that calls the
+	// settingsFn everytime the Allow function is called and swaps in the new settings in-place if
+	// there is a change in the settings object. The underlying state machine is implemented
+	// directly (mirroring gobreaker.TwoStepCircuitBreaker's Closed/Open/HalfOpen semantics) so
+	// that its state, counts, and expiry survive a settings change instead of being reset.
 	TwoStepCircuitBreakerWithDynamicSettings struct {
-		cb *gobreaker.TwoStepCircuitBreaker
+		core *coreBreaker
 
 		settingsFn   func() map[string]any
 		baseSettings baseSettings
@@ -66,7 +67,7 @@ func (c *TwoStepCircuitBreakerWithDynamicSettings) WithName(
 		return nil
 	}
 	ret := *c
-	ret.cb = nil
+	ret.core = nil
 	ret.name = name
 	return &ret
 }
@@ -78,7 +79,7 @@ func (c *TwoStepCircuitBreakerWithDynamicSettings) WithReadyToTrip(
 		return nil
 	}
 	ret := *c
-	ret.cb = nil
+	ret.core = nil
 	ret.readyToTrip = readyToTrip
 	return &ret
 }
@@ -90,7 +91,7 @@ func (c *TwoStepCircuitBreakerWithDynamicSettings) WithOnStateChange(
 		return nil
 	}
 	ret := *c
-	ret.cb = nil
+	ret.core = nil
 	ret.onStateChange = onStateChange
 	return &ret
 }
@@ -102,40 +103,41 @@ func (c *TwoStepCircuitBreakerWithDynamicSettings) WithIsSuccessful(
 		return nil
 	}
 	ret := *c
-	ret.cb = nil
+	ret.core = nil
 	ret.isSuccessful = isSuccessful
 	return &ret
 }
 
 func (c *TwoStepCircuitBreakerWithDynamicSettings) Name() string {
-	if c.cb == nil {
+	if c.core == nil {
 		return ""
 	}
-	return c.cb.Name()
+	return c.core.Name()
 }
 
 func (c *TwoStepCircuitBreakerWithDynamicSettings) State() gobreaker.State {
-	if c.cb == nil {
+	if c.core == nil {
 		return 0
 	}
-	return c.cb.State()
+	return c.core.State()
 }
 
 func (c *TwoStepCircuitBreakerWithDynamicSettings) Counts() gobreaker.Counts {
-	if c.cb == nil {
+	if c.core == nil {
 		return gobreaker.Counts{}
 	}
-	return c.cb.Counts()
+	return c.core.Counts()
 }
 
 func (c *TwoStepCircuitBreakerWithDynamicSettings) Allow() (done func(success bool), err error) {
-	if err := c.checkAndUpdateSettings(); err != nil {
-		return nil, err
-	}
-	return c.cb.Allow()
+	c.checkAndUpdateSettings()
+	return c.core.Allow()
 }
 
-func (c *TwoStepCircuitBreakerWithDynamicSettings) checkAndUpdateSettings() error {
+// checkAndUpdateSettings swaps in the latest settings in-place via the underlying coreBreaker,
+// preserving its current state, counts, and expiry rather than discarding them the way
+// constructing a brand new gobreaker.TwoStepCircuitBreaker would.
+func (c *TwoStepCircuitBreakerWithDynamicSettings) checkAndUpdateSettings() {
 	settingsMap := c.settingsFn()
 	bs := baseSettings{
 		MaxRequests: defaultMaxRequests,
@@ -159,19 +161,13 @@ func (c *TwoStepCircuitBreakerWithDynamicSettings) checkAndUpdateSettings() erro
 		) * time.Second
 	}
 
-	if c.cb != nil && bs == c.baseSettings {
-		return nil
+	if c.core != nil && bs == c.baseSettings {
+		return
 	}
 
 	c.baseSettings = bs
-	c.cb = gobreaker.NewTwoStepCircuitBreaker(gobreaker.Settings{
-		Name:          c.name,
-		MaxRequests:   bs.MaxRequests,
-		Interval:      bs.Interval,
-		Timeout:       bs.Timeout,
-		ReadyToTrip:   c.readyToTrip,
-		OnStateChange: c.onStateChange,
-		IsSuccessful:  c.isSuccessful,
-	})
-	return nil
+	if c.core == nil {
+		c.core = newCoreBreaker(c.name)
+	}
+	c.core.updateSettings(bs.MaxRequests, bs.Interval, bs.Timeout, c.readyToTrip, c.onStateChange)
 }
@@ -0,0 +1,95 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/require"
+)
+
+func alwaysTrip(counts gobreaker.Counts) bool {
+	return counts.ConsecutiveFailures > 0
+}
+
+func TestTwoStepCircuitBreakerWithDynamicSettings_PreservesStateAcrossSettingsChange_Open(t *testing.T) {
+	settings := map[string]any{
+		timeoutKey: 3600, // seconds; long enough that the breaker won't transition to HalfOpen on its own.
+	}
+	cb := NewTwoStepCircuitBreakerWithDynamicSettings(func() map[string]any { return settings }).
+		WithReadyToTrip(alwaysTrip)
+
+	done, err := cb.Allow()
+	require.NoError(t, err)
+	done(false)
+	require.Equal(t, gobreaker.StateOpen, cb.State())
+
+	// Flipping the Timeout while Open must not throw away the breaker and reset it to Closed;
+	// it should stay Open until the expiry that was already committed at trip time elapses.
+	settings = map[string]any{timeoutKey: 1}
+	require.Equal(t, gobreaker.StateOpen, cb.State())
+
+	_, err = cb.Allow()
+	require.ErrorIs(t, err, gobreaker.ErrOpenState)
+}
+
+func TestTwoStepCircuitBreakerWithDynamicSettings_PreservesStateAcrossSettingsChange_HalfOpen(t *testing.T) {
+	settings := map[string]any{timeoutKey: 0} // defaults to 60s timeout; the expiry is forced into the past below.
+	cb := NewTwoStepCircuitBreakerWithDynamicSettings(func() map[string]any { return settings }).
+		WithReadyToTrip(alwaysTrip)
+
+	// Trip the breaker; its expiry is then moved into the past so that it observes HalfOpen.
+	settings = map[string]any{timeoutKey: 0}
+	done, err := cb.Allow()
+	require.NoError(t, err)
+	done(false)
+	require.Equal(t, gobreaker.StateOpen, cb.State())
+
+	// Change the settings to MaxRequests 1 and force the expiry into the past directly before
+	// checking state again.
+	settings = map[string]any{maxRequestsKey: 1}
+	cb.core.mu.Lock()
+	cb.core.expiry = time.Now().Add(-time.Second)
+	cb.core.mu.Unlock()
+
+	require.Equal(t, gobreaker.StateHalfOpen, cb.State())
+
+	// The trial request's done callback must still resolve and close the breaker rather than
+	// silently being dropped as stale.
+	halfOpenDone, err := cb.Allow()
+	require.NoError(t, err)
+
+	// Flip MaxRequests in the settings source while the trial request is in flight. The breaker
+	// reads settings only on Allow, so this done call still resolves under MaxRequests 1.
+	settings = map[string]any{maxRequestsKey: 5}
+	halfOpenDone(true)
+
+	require.Equal(t, gobreaker.StateClosed, cb.State())
+}
+
+func TestTwoStepCircuitBreakerWithDynamicSettings_IntervalChangeAppliesImmediatelyWhileClosed(t *testing.T) {
+	settings := map[string]any{intervalKey: 0} // no count-reset window to start.
+	cb := NewTwoStepCircuitBreakerWithDynamicSettings(func() map[string]any { return settings })
+
+	done, err := cb.Allow()
+	require.NoError(t, err)
+	done(true)
+	require.Equal(t, uint32(1), cb.Counts().Requests)
+
+	// Turn on a short Interval while the breaker stays continuously Closed. With a zero Interval the
+	// window never ends, so it would only get recomputed on a state transition; without re-basing it
+	// here on a settings change the new window would never take effect and counts would just keep
+	// accumulating forever.
+	settings = map[string]any{intervalKey: 1}
+	done, err = cb.Allow() // observes the new settings and re-bases the window from now.
+	require.NoError(t, err)
+	done(true)
+	require.Equal(t, uint32(2), cb.Counts().Requests)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	done, err = cb.Allow()
+	require.NoError(t, err)
+	done(true)
+	require.Equal(t, uint32(1), cb.Counts().Requests, "the 1s Interval window should have reset counts")
+}
@@ -0,0 +1,204 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// coreBreaker implements the gobreaker.TwoStepCircuitBreaker state machine (Closed/Open/HalfOpen
+// with a generation counter) directly, rather than delegating to a *gobreaker.TwoStepCircuitBreaker
+// instance. This allows callers to swap in new parameters (max requests, timeout, trip condition)
+// in-place via updateSettings without discarding the current state, counts, or expiry the way
+// constructing a brand new gobreaker instance would.
+type coreBreaker struct {
+	mu sync.Mutex
+
+	name          string
+	maxRequests   uint32
+	interval      time.Duration
+	timeout       time.Duration
+	readyToTrip   func(counts gobreaker.Counts) bool
+	onStateChange func(name string, from gobreaker.State, to gobreaker.State)
+
+	state      gobreaker.State
+	generation uint64
+	counts     gobreaker.Counts
+	expiry     time.Time
+}
+
+func newCoreBreaker(name string) *coreBreaker {
+	cb := &coreBreaker{name: name}
+	cb.toNewGeneration(time.Now())
+	return cb
+}
+
+func (cb *coreBreaker) Name() string {
+	return cb.name
+}
+
+func (cb *coreBreaker) State() gobreaker.State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	state, _ := cb.currentState(time.Now())
+	return state
+}
+
+func (cb *coreBreaker) Counts() gobreaker.Counts {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.counts
+}
+
+// updateSettings swaps in new parameters in-place, preserving the current state and counts rather
+// than resetting them. The Closed-state count-reset window is the one exception: it is otherwise
+// only recomputed on a state transition or when the current window ends, so a changed Interval
+// would not take effect until then (and never, while the breaker stays Closed, if the old Interval
+// was zero). A change to Interval therefore re-bases that window from now immediately, the same
+// way the old rebuild-the-whole-breaker approach applied it right away.
+func (cb *coreBreaker) updateSettings(
+	maxRequests uint32,
+	interval time.Duration,
+	timeout time.Duration,
+	readyToTrip func(counts gobreaker.Counts) bool,
+	onStateChange func(name string, from gobreaker.State, to gobreaker.State),
+) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.maxRequests = maxRequests
+	intervalChanged := cb.interval != interval
+	cb.interval = interval
+	cb.timeout = timeout
+	cb.readyToTrip = readyToTrip
+	cb.onStateChange = onStateChange
+
+	if intervalChanged && cb.state == gobreaker.StateClosed {
+		if cb.interval == 0 {
+			cb.expiry = time.Time{}
+		} else {
+			cb.expiry = time.Now().Add(cb.interval)
+		}
+	}
+}
+
+func (cb *coreBreaker) Allow() (done func(success bool), err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+	state, generation := cb.currentState(now)
+
+	if state == gobreaker.StateOpen {
+		return nil, gobreaker.ErrOpenState
+	} else if state == gobreaker.StateHalfOpen && cb.counts.Requests >= cb.effectiveMaxRequests() {
+		return nil, gobreaker.ErrTooManyRequests
+	}
+
+	cb.counts.Requests++
+	return func(success bool) {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+		cb.afterRequest(generation, success)
+	}, nil
+}
+
+// afterRequest is a no-op if the breaker has moved on to a new generation since the corresponding
+// Allow call, mirroring gobreaker's handling of stale in-flight requests.
+func (cb *coreBreaker) afterRequest(before uint64, success bool) {
+	now := time.Now()
+	state, generation := cb.currentState(now)
+	if generation != before {
+		return
+	}
+
+	if success {
+		cb.counts.TotalSuccesses++
+		cb.counts.ConsecutiveSuccesses++
+		cb.counts.ConsecutiveFailures = 0
+	} else {
+		cb.counts.TotalFailures++
+		cb.counts.ConsecutiveFailures++
+		cb.counts.ConsecutiveSuccesses = 0
+	}
+
+	switch state {
+	case gobreaker.StateClosed:
+		if cb.effectiveReadyToTrip(cb.counts) {
+			cb.setState(gobreaker.StateOpen, now)
+		}
+	case gobreaker.StateHalfOpen:
+		if !success {
+			cb.setState(gobreaker.StateOpen, now)
+		} else if cb.counts.ConsecutiveSuccesses >= cb.effectiveMaxRequests() {
+			cb.setState(gobreaker.StateClosed, now)
+		}
+	}
+}
+
+func (cb *coreBreaker) currentState(now time.Time) (gobreaker.State, uint64) {
+	switch cb.state {
+	case gobreaker.StateClosed:
+		if !cb.expiry.IsZero() && !cb.expiry.After(now) {
+			cb.toNewGeneration(now)
+		}
+	case gobreaker.StateOpen:
+		if !cb.expiry.IsZero() && !cb.expiry.After(now) {
+			cb.setState(gobreaker.StateHalfOpen, now)
+		}
+	}
+	return cb.state, cb.generation
+}
+
+func (cb *coreBreaker) setState(state gobreaker.State, now time.Time) {
+	if cb.state == state {
+		return
+	}
+	prev := cb.state
+	cb.state = state
+	cb.toNewGeneration(now)
+	if cb.onStateChange != nil {
+		cb.onStateChange(cb.name, prev, state)
+	}
+}
+
+func (cb *coreBreaker) toNewGeneration(now time.Time) {
+	cb.generation++
+	cb.counts = gobreaker.Counts{}
+
+	switch cb.state {
+	case gobreaker.StateClosed:
+		if cb.interval == 0 {
+			cb.expiry = time.Time{}
+		} else {
+			cb.expiry = now.Add(cb.interval)
+		}
+	case gobreaker.StateOpen:
+		cb.expiry = now.Add(cb.effectiveTimeout())
+	default:
+		cb.expiry = time.Time{}
+	}
+}
+
+func (cb *coreBreaker) effectiveMaxRequests() uint32 {
+	if cb.maxRequests == 0 {
+		return 1
+	}
+	return cb.maxRequests
+}
+
+// effectiveReadyToTrip falls back to gobreaker's default trip condition of more than 5
+// consecutive failures when no readyToTrip is set.
+func (cb *coreBreaker) effectiveReadyToTrip(counts gobreaker.Counts) bool {
+	if cb.readyToTrip == nil {
+		return counts.ConsecutiveFailures > 5
+	}
+	return cb.readyToTrip(counts)
+}
+
+func (cb *coreBreaker) effectiveTimeout() time.Duration {
+	if cb.timeout == 0 {
+		return 60 * time.Second
+	}
+	return cb.timeout
+}
@@ -24,12 +24,19 @@ package hsm
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 // ErrInvalidTaskKind can be returned by a [TaskSerializer] if it received the wrong task kind.
 var ErrInvalidTaskKind = errors.New("invalid task kind")
 
+// ErrInvalidRecurringSchedule is returned by [TaskKindRecurring.Validate] when a schedule spec
+// sets neither or both of Cron and Interval, or sets an invalid Cron expression.
+var ErrInvalidRecurringSchedule = errors.New("exactly one of Cron or Interval must be set")
+
 // Task type.
 type TaskType struct {
 	// Type ID that is used to minimize the persistence storage space and look up the regisered serializer.
@@ -90,6 +97,99 @@ type TaskKindOutbound struct {
 	Destination string
 }
 
+// TaskKindRecurring is a task that fires repeatedly on a cron or fixed-interval schedule, without
+// requiring the owning state machine to transition between executions.
+// [TaskKindRecurring.Reschedule] computes, after an execution, whether that tick fires and when the
+// next one is due. Nothing in this package calls Reschedule or re-schedules the task on its own.
+//
+// Exactly one of Cron or Interval must be set; use [TaskKindRecurring.Validate] to check this
+// before scheduling the first tick.
+type TaskKindRecurring struct {
+	unimplementedTaskKind
+	// Cron expression describing the recurrence schedule, parsed with robfig/cron/v3. Mutually
+	// exclusive with Interval.
+	Cron string
+	// Fixed recurrence interval. Mutually exclusive with Cron.
+	Interval time.Duration
+	// Fixed offset added to each computed fire time. The same offset is added every time; it is not
+	// randomized.
+	Jitter time.Duration
+	// Maximum number of times this task will fire. Zero means unlimited.
+	MaxRuns int
+}
+
+// Validate returns ErrInvalidRecurringSchedule unless exactly one of Cron or Interval is set, and
+// Cron, if set, is a valid robfig/cron/v3 expression.
+func (k TaskKindRecurring) Validate() error {
+	if (k.Cron == "") == (k.Interval <= 0) {
+		return ErrInvalidRecurringSchedule
+	}
+	if k.Cron != "" {
+		if _, err := cron.ParseStandard(k.Cron); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRecurringSchedule, err)
+		}
+	}
+	return nil
+}
+
+// NextFireTime returns the next time this recurring task should fire after last, parsing Cron with
+// robfig/cron/v3 when set, or otherwise adding Interval.
+func (k TaskKindRecurring) NextFireTime(last time.Time) (time.Time, error) {
+	if err := k.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	if k.Cron != "" {
+		schedule, err := cron.ParseStandard(k.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return schedule.Next(last).Add(k.Jitter), nil
+	}
+	return last.Add(k.Interval).Add(k.Jitter), nil
+}
+
+// Done reports whether a recurring task has reached MaxRuns and should not be rescheduled again.
+// A MaxRuns of zero means unlimited, so Done always returns false in that case.
+func (k TaskKindRecurring) Done(runsCompleted int) bool {
+	return k.MaxRuns > 0 && runsCompleted >= k.MaxRuns
+}
+
+// Reschedule computes what the runtime should do with a recurring task after an execution tick.
+// It returns fire=false, with no error, once MaxRuns has been reached, and the task should not be
+// scheduled again.
+//
+// Otherwise it honors the staleness check documented on [Task] for non-concurrent tasks: if
+// currentTransitionCount (the machine's live transition count) does not match
+// taskTransitionCount (the transition count captured in the task's [Ref] when this tick was
+// generated), the tick is stale and fire is false, but the task is still rescheduled - from now
+// rather than from lastFireTime - so a transitioned machine does not get flooded with catch-up
+// ticks once it starts firing again. Concurrent tasks are never stale and should pass equal
+// counts.
+func (k TaskKindRecurring) Reschedule(
+	runsCompleted int,
+	lastFireTime time.Time,
+	currentTransitionCount, taskTransitionCount int64,
+) (nextFireTime time.Time, fire bool, done bool, err error) {
+	if err := k.Validate(); err != nil {
+		return time.Time{}, false, false, err
+	}
+	if k.Done(runsCompleted) {
+		return time.Time{}, false, true, nil
+	}
+
+	fire = currentTransitionCount == taskTransitionCount
+
+	from := lastFireTime
+	if !fire {
+		from = time.Now()
+	}
+	nextFireTime, err = k.NextFireTime(from)
+	if err != nil {
+		return time.Time{}, false, false, err
+	}
+	return nextFireTime, fire, false, nil
+}
+
 // TaskSerializer provides type information and a serializer for a state machine.
 type TaskSerializer interface {
 	Serialize(Task) ([]byte, error)
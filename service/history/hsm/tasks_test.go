@@ -0,0 +1,86 @@
+package hsm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskKindRecurring_Validate(t *testing.T) {
+	require.ErrorIs(t, TaskKindRecurring{}.Validate(), ErrInvalidRecurringSchedule)
+	require.ErrorIs(
+		t,
+		TaskKindRecurring{Cron: "* * * * *", Interval: time.Minute}.Validate(),
+		ErrInvalidRecurringSchedule,
+	)
+	require.ErrorIs(t, TaskKindRecurring{Cron: "not a cron expression"}.Validate(), ErrInvalidRecurringSchedule)
+	require.NoError(t, TaskKindRecurring{Interval: time.Minute}.Validate())
+	require.NoError(t, TaskKindRecurring{Cron: "* * * * *"}.Validate())
+}
+
+func TestTaskKindRecurring_NextFireTime_Interval(t *testing.T) {
+	k := TaskKindRecurring{Interval: time.Minute, Jitter: time.Second}
+	last := time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC)
+
+	next, err := k.NextFireTime(last)
+	require.NoError(t, err)
+	require.Equal(t, last.Add(time.Minute).Add(time.Second), next)
+}
+
+func TestTaskKindRecurring_NextFireTime_Cron(t *testing.T) {
+	k := TaskKindRecurring{Cron: "0 * * * *"}
+	last := time.Date(2026, 7, 27, 12, 30, 0, 0, time.UTC)
+
+	next, err := k.NextFireTime(last)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 7, 27, 13, 0, 0, 0, time.UTC), next)
+}
+
+func TestTaskKindRecurring_Done(t *testing.T) {
+	unlimited := TaskKindRecurring{Interval: time.Minute}
+	require.False(t, unlimited.Done(1_000_000))
+
+	limited := TaskKindRecurring{Interval: time.Minute, MaxRuns: 3}
+	require.False(t, limited.Done(2))
+	require.True(t, limited.Done(3))
+	require.True(t, limited.Done(4))
+}
+
+func TestTaskKindRecurring_Reschedule_FiresAndAdvancesWhenNotStale(t *testing.T) {
+	k := TaskKindRecurring{Interval: time.Minute}
+	last := time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC)
+
+	next, fire, done, err := k.Reschedule(0, last, 5, 5)
+	require.NoError(t, err)
+	require.True(t, fire)
+	require.False(t, done)
+	require.Equal(t, last.Add(time.Minute), next)
+}
+
+func TestTaskKindRecurring_Reschedule_SkipsStaleTickButStillReschedules(t *testing.T) {
+	k := TaskKindRecurring{Interval: time.Minute}
+	staleLastFireTime := time.Now().Add(-time.Hour)
+
+	next, fire, done, err := k.Reschedule(0, staleLastFireTime, 6, 5)
+	require.NoError(t, err)
+	require.False(t, fire)
+	require.False(t, done)
+	// Rescheduled from now, not from the stale lastFireTime, so it's not an hour behind.
+	require.WithinDuration(t, time.Now().Add(time.Minute), next, 5*time.Second)
+}
+
+func TestTaskKindRecurring_Reschedule_DoneAfterMaxRuns(t *testing.T) {
+	k := TaskKindRecurring{Interval: time.Minute, MaxRuns: 3}
+
+	next, fire, done, err := k.Reschedule(3, time.Now(), 1, 1)
+	require.NoError(t, err)
+	require.False(t, fire)
+	require.True(t, done)
+	require.True(t, next.IsZero())
+}
+
+func TestTaskKindRecurring_Reschedule_InvalidSchedule(t *testing.T) {
+	_, _, _, err := TaskKindRecurring{}.Reschedule(0, time.Now(), 1, 1)
+	require.ErrorIs(t, err, ErrInvalidRecurringSchedule)
+}